@@ -0,0 +1,305 @@
+package terminfo
+
+// StringCapType identifies a standard string capability by its index into a
+// Terminfo's Strings slice. The Bool*/Num* capability constants below index
+// Bools/Nums the same way but, since those slices are read directly rather
+// than through Parm, are left as plain untyped int constants.
+type StringCapType int
+
+// Bool capability indices, in the order they are stored on disk and in
+// BoolCapNames.
+const (
+	AutoLeftMargin = iota
+	AutoRightMargin
+	BackColorErase
+	CanChange
+	CeolStandoutGlitch
+	ColAddrGlitch
+	CpiChangesRes
+	CrCancelsMicroMode
+	DestTabsMagicSmso
+	EatNewlineGlitch
+	EraseOverstrike
+	GenericType
+	HardCopy
+	HardCursor
+	HasMetaKey
+	HasPrintWheel
+	HasStatusLine
+	HueLightnessSaturation
+	InsertNullGlitch
+	LpiChangesRes
+	MemoryAbove
+	MemoryBelow
+	MoveInsertMode
+	MoveStandoutMode
+	NeedsXonXoff
+	NoEscCtlc
+	NoPadChar
+	NonDestScrollRegion
+	NonRevRmcup
+	OverStrike
+	StatusLineEscOk
+	TildeGlitch
+	TransparentUnderline
+	XonXoff
+)
+
+// BoolCapNames are the short terminfo(5) names for the bool capabilities
+// above, in the same order.
+var BoolCapNames = []string{
+	"bw",
+	"am",
+	"bce",
+	"ccc",
+	"xhp",
+	"xhpa",
+	"cpix",
+	"crxm",
+	"xt",
+	"xenl",
+	"eo",
+	"gn",
+	"hc",
+	"chts",
+	"km",
+	"daisy",
+	"hs",
+	"hls",
+	"in",
+	"lpix",
+	"da",
+	"db",
+	"mir",
+	"msgr",
+	"nxon",
+	"xsb",
+	"npc",
+	"ndscr",
+	"nrrmc",
+	"os",
+	"eslok",
+	"hz",
+	"ul",
+	"xon",
+}
+
+// Num capability indices, in the order they are stored on disk and in
+// NumCapNames.
+const (
+	Columns = iota
+	InitTabs
+	Lines
+	LinesOfMemory
+	MagicCookieGlitch
+	PaddingBaudRate
+	VirtualTerminal
+	WidthStatusLine
+	NumLabels
+	LabelHeight
+	LabelWidth
+	MaxAttributes
+	MaximumWindows
+	MaxColors
+	MaxPairs
+	NoColorVideo
+	BufferCapacity
+	DotVertSpacing
+	DotHorzSpacing
+)
+
+// NumCapNames are the short terminfo(5) names for the num capabilities
+// above, in the same order.
+var NumCapNames = []string{
+	"cols",
+	"it",
+	"lines",
+	"lm",
+	"xmc",
+	"pb",
+	"vt",
+	"wsl",
+	"nlab",
+	"lh",
+	"lw",
+	"ma",
+	"wnum",
+	"colors",
+	"pairs",
+	"ncv",
+	"bufsz",
+	"spinv",
+	"spinh",
+}
+
+// String capability indices, in the order they are stored on disk and in
+// StringCapNames.
+const (
+	Bell StringCapType = iota
+	CarriageReturn
+	ClearScreen
+	ClrEol
+	ClrEos
+	ColumnAddress
+	CursorAddress
+	CursorDown
+	CursorHome
+	CursorInvisible
+	CursorLeft
+	CursorNormal
+	CursorRight
+	CursorUp
+	CursorVisible
+	DeleteCharacter
+	DeleteLine
+	EnterBlinkMode
+	EnterBoldMode
+	EnterDimMode
+	EnterReverseMode
+	EnterStandoutMode
+	EnterUnderlineMode
+	EraseChars
+	ExitAltCharsetMode
+	EnterAltCharsetMode
+	ExitAttributeMode
+	ExitStandoutMode
+	ExitUnderlineMode
+	InsertCharacter
+	InsertLine
+	KeypadLocal
+	KeypadXmit
+	Newline
+	ParmDch
+	ParmDeleteLine
+	ParmDownCursor
+	ParmIch
+	ParmIndex
+	ParmInsertLine
+	ParmLeftCursor
+	ParmRightCursor
+	ParmRindex
+	ParmUpCursor
+	PadChar
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyDc
+	KeyIc
+	KeyNpage
+	KeyPpage
+	OrigPair
+	OrigColors
+	SetAForeground
+	SetABackground
+	SetForeground
+	SetBackground
+	SetColorPair
+	SetAttributes
+	SaveCursor
+	RestoreCursor
+	ScrollForward
+	ScrollReverse
+	Tab
+	BackTab
+	FlashScreen
+	Init1String
+	Init2String
+	Init3String
+	Reset1String
+	Reset2String
+	Reset3String
+	ClearMargins
+	SetLrMargin
+	AcsChars
+	ToStatusLine
+	FromStatusLine
+)
+
+// StringCapNames are the short terminfo(5) names for the string
+// capabilities above, in the same order.
+var StringCapNames = []string{
+	"bel",
+	"cr",
+	"clear",
+	"el",
+	"ed",
+	"hpa",
+	"cup",
+	"cud1",
+	"home",
+	"civis",
+	"cub1",
+	"cnorm",
+	"cuf1",
+	"cuu1",
+	"cvvis",
+	"dch1",
+	"dl1",
+	"blink",
+	"bold",
+	"dim",
+	"rev",
+	"smso",
+	"smul",
+	"ech",
+	"rmacs",
+	"smacs",
+	"sgr0",
+	"rmso",
+	"rmul",
+	"ich1",
+	"il1",
+	"rmkx",
+	"smkx",
+	"nel",
+	"dch",
+	"dl",
+	"cud",
+	"ich",
+	"indn",
+	"il",
+	"cub",
+	"cuf",
+	"rin",
+	"cuu",
+	"pad",
+	"kbs",
+	"kcuu1",
+	"kcud1",
+	"kcub1",
+	"kcuf1",
+	"khome",
+	"kdch1",
+	"kich1",
+	"knp",
+	"kpp",
+	"op",
+	"oc",
+	"setaf",
+	"setab",
+	"setf",
+	"setb",
+	"scp",
+	"sgr",
+	"sc",
+	"rc",
+	"ind",
+	"ri",
+	"ht",
+	"cbt",
+	"flash",
+	"is1",
+	"is2",
+	"is3",
+	"rs1",
+	"rs2",
+	"rs3",
+	"mgc",
+	"smglr",
+	"acsc",
+	"tsl",
+	"fsl",
+}
@@ -0,0 +1,65 @@
+// Command gotic compiles terminfo source files, as produced by infocmp and
+// consumed by tic, into the binary entries read by terminfo.Decode, laying
+// them out in the usual t/term directory tree.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/isgasho/terminfo"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: gotic source.ti [outdir]")
+		os.Exit(2)
+	}
+
+	outDir := "."
+	if len(os.Args) > 2 {
+		outDir = os.Args[2]
+	}
+
+	if err := run(os.Args[1], outDir); err != nil {
+		fmt.Fprintln(os.Stderr, "gotic:", err)
+		os.Exit(1)
+	}
+}
+
+func run(srcFile, outDir string) error {
+	src, err := ioutil.ReadFile(srcFile)
+	if err != nil {
+		return err
+	}
+
+	entries, err := terminfo.Compile(src)
+	if err != nil {
+		return err
+	}
+
+	for _, ti := range entries {
+		if err := writeEntry(ti, outDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeEntry(ti *terminfo.Terminfo, outDir string) error {
+	buf, err := ti.Encode()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ti.Names[0], err)
+	}
+
+	name := ti.Names[0]
+	dir := path.Join(outDir, name[0:1])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(dir, name), buf, 0644)
+}
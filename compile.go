@@ -0,0 +1,397 @@
+package terminfo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownUse is the error returned by Compile when a use= clause
+// references a name that has not (yet) been defined earlier in src.
+var ErrUnknownUse = errors.New("terminfo: use= references unknown entry")
+
+// Compile parses src, a terminfo source file in the human-readable format
+// produced by infocmp and consumed by tic, and returns the entries it
+// describes.
+//
+// Entries are separated by blank lines. Each begins with a comma-terminated,
+// pipe-delimited list of names (e.g. "xterm-256color|xterm with 256
+// colors,"), followed by indented, comma-separated capability assignments: a
+// bare name ("bw") is a boolean capability, "name#value" a numeric one
+// (value may be decimal, "0"-prefixed octal, or "0x"-prefixed hex),
+// "name=value" a string one (value using the usual \E \n \r \t \b \f \s \0,
+// octal \nnn, and ^X escapes), and "name@" cancels a capability inherited
+// via a "use=other" clause. A trailing backslash continues an assignment
+// line, and lines starting with '#' are comments. Capability names not
+// recognized as standard are stored in the Ext* maps.
+func Compile(src []byte) ([]*Terminfo, error) {
+	byName := make(map[string]*Terminfo)
+
+	var out []*Terminfo
+	for _, lines := range splitEntries(src) {
+		ti, err := compileEntry(lines, byName)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, ti)
+		for _, n := range ti.Names {
+			byName[n] = ti
+		}
+	}
+
+	return out, nil
+}
+
+// splitEntries splits src into entries: blank-line separated groups of
+// logical lines, with backslash continuations joined and '#' comment
+// lines dropped.
+func splitEntries(src []byte) [][]string {
+	var entries [][]string
+	var cur []string
+	var pending string
+
+	for _, raw := range strings.Split(string(src), "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+
+		if pending == "" && strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			pending += line[:len(line)-1]
+			continue
+		}
+		line, pending = pending+line, ""
+
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				entries = append(entries, cur)
+				cur = nil
+			}
+			continue
+		}
+
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		entries = append(entries, cur)
+	}
+
+	return entries
+}
+
+// compileEntry parses a single blank-line-delimited entry (its first line
+// being the names line, the rest capability assignments).
+func compileEntry(lines []string, byName map[string]*Terminfo) (*Terminfo, error) {
+	if len(lines) == 0 {
+		return nil, ErrEmptyTermName
+	}
+
+	nameLine := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(lines[0]), ","))
+	names := strings.Split(nameLine, "|")
+	if len(names) == 0 || names[0] == "" {
+		return nil, ErrEmptyTermName
+	}
+
+	ti := &Terminfo{
+		Names:           names,
+		Bools:           make([]bool, len(BoolCapNames)),
+		BoolsM:          make(map[int]bool, len(BoolCapNames)),
+		Nums:            make([]int, len(NumCapNames)),
+		NumsM:           make(map[int]bool, len(NumCapNames)),
+		Strings:         make([]string, len(StringCapNames)),
+		StringsM:        make(map[int]bool, len(StringCapNames)),
+		ExtBoolsNames:   make(map[string]int),
+		ExtNumsNames:    make(map[string]int),
+		ExtStringsNames: make(map[string]int),
+	}
+	for i := range ti.Bools {
+		ti.BoolsM[i] = true
+	}
+	for i := range ti.Nums {
+		ti.NumsM[i] = true
+	}
+	for i := range ti.Strings {
+		ti.StringsM[i] = true
+	}
+
+	var body strings.Builder
+	for _, l := range lines[1:] {
+		body.WriteString(strings.TrimSpace(l))
+		body.WriteByte(',')
+	}
+
+	var cancelled []string
+	for _, tok := range splitCaps(body.String()) {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+
+		if strings.HasPrefix(tok, "use=") {
+			use := tok[len("use="):]
+			other, ok := byName[use]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownUse, use)
+			}
+			mergeEntry(ti, other)
+			// cancellations made before use= still apply to what it merged
+			for _, name := range cancelled {
+				cancelCap(ti, name)
+			}
+			continue
+		}
+
+		if strings.HasSuffix(tok, "@") {
+			cancelled = append(cancelled, tok[:len(tok)-1])
+		}
+
+		if err := assignCap(ti, tok); err != nil {
+			return nil, err
+		}
+	}
+
+	return ti, nil
+}
+
+// splitCaps splits s on commas that are not escaped with a backslash.
+func splitCaps(s string) []string {
+	var toks []string
+	var cur strings.Builder
+	esc := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case esc:
+			cur.WriteByte(c)
+			esc = false
+		case c == '\\':
+			cur.WriteByte(c)
+			esc = true
+		case c == ',':
+			toks = append(toks, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if cur.Len() > 0 {
+		toks = append(toks, cur.String())
+	}
+	return toks
+}
+
+// assignCap applies a single "name", "name@", "name#value", or "name=value"
+// capability assignment token to ti.
+func assignCap(ti *Terminfo, tok string) error {
+	switch eq := strings.IndexByte(tok, '='); {
+	case eq >= 0 && !strings.ContainsAny(tok[:eq], "#@"):
+		setStringCap(ti, tok[:eq], unescapeCapString(tok[eq+1:]))
+		return nil
+
+	case strings.HasSuffix(tok, "@"):
+		cancelCap(ti, tok[:len(tok)-1])
+		return nil
+
+	case strings.Contains(tok, "#"):
+		parts := strings.SplitN(tok, "#", 2)
+		n, err := strconv.ParseInt(parts[1], 0, 64)
+		if err != nil {
+			return fmt.Errorf("terminfo: invalid numeric capability %q: %w", tok, err)
+		}
+		setNumCap(ti, parts[0], int(n))
+		return nil
+
+	default:
+		setBoolCap(ti, tok, true)
+		return nil
+	}
+}
+
+// unescapeCapString decodes the backslash and caret escapes used in
+// terminfo source string capability values: \E \n \r \t \b \f \s \0,
+// octal \nnn, literal \\ \, \:, and ^X control characters.
+func unescapeCapString(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch e := s[i]; {
+			case e == 'E':
+				buf.WriteByte(0x1b)
+			case e == 'n':
+				buf.WriteByte('\n')
+			case e == 'r':
+				buf.WriteByte('\r')
+			case e == 't':
+				buf.WriteByte('\t')
+			case e == 'b':
+				buf.WriteByte('\b')
+			case e == 'f':
+				buf.WriteByte('\f')
+			case e == 's':
+				buf.WriteByte(' ')
+			case e >= '0' && e <= '7':
+				j := i
+				for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+					j++
+				}
+				v, _ := strconv.ParseUint(s[i:j], 8, 8)
+				buf.WriteByte(byte(v))
+				i = j - 1
+			default:
+				buf.WriteByte(e)
+			}
+		case c == '^' && i+1 < len(s):
+			i++
+			buf.WriteByte(s[i] & 0x1f)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+func setBoolCap(ti *Terminfo, name string, v bool) {
+	if i := indexOf(BoolCapNames, name); i >= 0 {
+		ti.Bools[i] = v
+		delete(ti.BoolsM, i)
+		return
+	}
+	i, ok := ti.ExtBoolsNames[name]
+	if !ok {
+		i = len(ti.ExtBools)
+		ti.ExtBools = append(ti.ExtBools, false)
+		ti.ExtBoolsNames[name] = i
+	}
+	ti.ExtBools[i] = v
+}
+
+func setNumCap(ti *Terminfo, name string, v int) {
+	if i := indexOf(NumCapNames, name); i >= 0 {
+		ti.Nums[i] = v
+		delete(ti.NumsM, i)
+		return
+	}
+	i, ok := ti.ExtNumsNames[name]
+	if !ok {
+		i = len(ti.ExtNums)
+		ti.ExtNums = append(ti.ExtNums, 0)
+		ti.ExtNumsNames[name] = i
+	}
+	ti.ExtNums[i] = v
+}
+
+func setStringCap(ti *Terminfo, name, v string) {
+	if i := indexOf(StringCapNames, name); i >= 0 {
+		ti.Strings[i] = v
+		delete(ti.StringsM, i)
+		return
+	}
+	i, ok := ti.ExtStringsNames[name]
+	if !ok {
+		i = len(ti.ExtStrings)
+		ti.ExtStrings = append(ti.ExtStrings, "")
+		ti.ExtStringsNames[name] = i
+	}
+	ti.ExtStrings[i] = v
+}
+
+// cancelCap marks name as explicitly cancelled: a standard capability
+// reverts to its zero value and its "missing" state, and an extended one is
+// removed from the Ext* slices and maps.
+func cancelCap(ti *Terminfo, name string) {
+	switch {
+	case indexOf(BoolCapNames, name) >= 0:
+		i := indexOf(BoolCapNames, name)
+		ti.Bools[i] = false
+		ti.BoolsM[i] = true
+	case indexOf(NumCapNames, name) >= 0:
+		i := indexOf(NumCapNames, name)
+		ti.Nums[i] = 0
+		ti.NumsM[i] = true
+	case indexOf(StringCapNames, name) >= 0:
+		i := indexOf(StringCapNames, name)
+		ti.Strings[i] = ""
+		ti.StringsM[i] = true
+	default:
+		if i, ok := ti.ExtBoolsNames[name]; ok {
+			ti.ExtBools = append(ti.ExtBools[:i], ti.ExtBools[i+1:]...)
+			removeExtName(ti.ExtBoolsNames, name, i)
+		}
+		if i, ok := ti.ExtNumsNames[name]; ok {
+			ti.ExtNums = append(ti.ExtNums[:i], ti.ExtNums[i+1:]...)
+			removeExtName(ti.ExtNumsNames, name, i)
+		}
+		if i, ok := ti.ExtStringsNames[name]; ok {
+			ti.ExtStrings = append(ti.ExtStrings[:i], ti.ExtStrings[i+1:]...)
+			removeExtName(ti.ExtStringsNames, name, i)
+		}
+	}
+}
+
+// removeExtName deletes name (at index i) from m and shifts the indices
+// after it down by one.
+func removeExtName(m map[string]int, name string, i int) {
+	delete(m, name)
+	for n, j := range m {
+		if j > i {
+			m[n] = j - 1
+		}
+	}
+}
+
+// mergeEntry merges other's capabilities into ti as defaults: any
+// capability ti has not already had an explicit assignment for is taken
+// from other, and nothing ti already has is overwritten. Assignments later
+// in ti's own source entry overwrite merged values as usual, so ti's own
+// assignments win whether they come before or after use=.
+func mergeEntry(ti, other *Terminfo) {
+	for i, v := range other.Bools {
+		if !other.BoolsM[i] && ti.BoolsM[i] {
+			ti.Bools[i] = v
+			delete(ti.BoolsM, i)
+		}
+	}
+	for i, v := range other.Nums {
+		if !other.NumsM[i] && ti.NumsM[i] {
+			ti.Nums[i] = v
+			delete(ti.NumsM, i)
+		}
+	}
+	for i, v := range other.Strings {
+		if !other.StringsM[i] && ti.StringsM[i] {
+			ti.Strings[i] = v
+			delete(ti.StringsM, i)
+		}
+	}
+	for name, i := range other.ExtBoolsNames {
+		if _, ok := ti.ExtBoolsNames[name]; !ok {
+			setBoolCap(ti, name, other.ExtBools[i])
+		}
+	}
+	for name, i := range other.ExtNumsNames {
+		if _, ok := ti.ExtNumsNames[name]; !ok {
+			setNumCap(ti, name, other.ExtNums[i])
+		}
+	}
+	for name, i := range other.ExtStringsNames {
+		if _, ok := ti.ExtStringsNames[name]; !ok {
+			setStringCap(ti, name, other.ExtStrings[i])
+		}
+	}
+}
+
+// indexOf returns the index of s within names, or -1 if not present.
+func indexOf(names []string, s string) int {
+	for i, n := range names {
+		if n == s {
+			return i
+		}
+	}
+	return -1
+}
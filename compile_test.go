@@ -0,0 +1,92 @@
+package terminfo
+
+import "testing"
+
+// numCapValue returns ti's value for the numeric capability named name,
+// checking the standard table first and then the extended one, however
+// Compile happened to place it.
+func numCapValue(ti *Terminfo, name string) (int, bool) {
+	if i := indexOf(NumCapNames, name); i >= 0 {
+		return ti.Nums[i], !ti.NumsM[i]
+	}
+	if i, ok := ti.ExtNumsNames[name]; ok {
+		return ti.ExtNums[i], true
+	}
+	return 0, false
+}
+
+// TestCompileUseLocalOverridesBeforeUse verifies that a capability assigned
+// directly in an entry wins over the same capability pulled in via use=,
+// even when the local assignment appears earlier in the entry than use=.
+func TestCompileUseLocalOverridesBeforeUse(t *testing.T) {
+	src := `base,
+	cols#80,
+
+child,
+	cols#132,
+	use=base,
+`
+
+	entries, err := Compile([]byte(src))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	v, ok := numCapValue(entries[1], "cols")
+	if !ok {
+		t.Fatalf("child entry missing cols capability")
+	}
+	if v != 132 {
+		t.Errorf("cols = %d, want 132 (local assignment must win over use=base)", v)
+	}
+}
+
+// TestCompileUseLocalOverridesAfterUse is the same as above but with use=
+// appearing before the local assignment, which must still win.
+func TestCompileUseLocalOverridesAfterUse(t *testing.T) {
+	src := `base,
+	cols#80,
+
+child,
+	use=base,
+	cols#132,
+`
+
+	entries, err := Compile([]byte(src))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	v, ok := numCapValue(entries[1], "cols")
+	if !ok {
+		t.Fatalf("child entry missing cols capability")
+	}
+	if v != 132 {
+		t.Errorf("cols = %d, want 132", v)
+	}
+}
+
+// TestCompileUseInheritsUnsetCapability checks that use= still supplies
+// capabilities the child entry doesn't mention at all.
+func TestCompileUseInheritsUnsetCapability(t *testing.T) {
+	src := `base,
+	cols#80,
+	lines#24,
+
+child,
+	cols#132,
+	use=base,
+`
+
+	entries, err := Compile([]byte(src))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	if v, ok := numCapValue(entries[1], "lines"); !ok || v != 24 {
+		t.Errorf("lines = %v, ok=%v, want 24, true (inherited from use=base)", v, ok)
+	}
+}
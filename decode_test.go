@@ -0,0 +1,77 @@
+package terminfo
+
+import "testing"
+
+// TestDecodeWideRoundTrip exercises the ncurses 6.1+ wide terminfo format:
+// a numeric capability larger than the legacy 16-bit range forces Encode to
+// emit the wide (magicWide) format, and Decode must read it back exactly,
+// including the large value, an absent numeric capability, and an
+// extended numeric capability.
+func TestDecodeWideRoundTrip(t *testing.T) {
+	const bigCols = 100000 // out of int16 range, requires the wide format
+
+	ti := &Terminfo{
+		Names:    []string{"wide-term"},
+		Bools:    []bool{true, false},
+		BoolsM:   map[int]bool{},
+		Nums:     []int{bigCols, 0},
+		NumsM:    map[int]bool{1: true},
+		Strings:  []string{"\x1b[%p1%dC"},
+		StringsM: map[int]bool{},
+		ExtNums:  []int{200000},
+		ExtNumsNames: map[string]int{
+			"xn": 0,
+		},
+	}
+
+	buf, err := ti.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got, err := Decode(buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if got.Nums[0] != bigCols {
+		t.Errorf("Nums[0] = %d, want %d", got.Nums[0], bigCols)
+	}
+	if !got.NumsM[1] {
+		t.Errorf("Nums[1] should be marked missing")
+	}
+	if len(got.ExtNums) != 1 || got.ExtNums[0] != 200000 {
+		t.Errorf("ExtNums = %v, want [200000]", got.ExtNums)
+	}
+}
+
+// TestDecodeLegacyRoundTrip is the same round trip, but with every numeric
+// capability within the legacy 16-bit range, which must stay on the
+// legacy (non-wide) magic.
+func TestDecodeLegacyRoundTrip(t *testing.T) {
+	ti := &Terminfo{
+		Names:    []string{"legacy-term"},
+		Bools:    []bool{true},
+		BoolsM:   map[int]bool{},
+		Nums:     []int{80},
+		NumsM:    map[int]bool{},
+		Strings:  []string{"\x1b[H"},
+		StringsM: map[int]bool{},
+	}
+
+	buf, err := ti.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if len(buf) < 2 || int(buf[0])|int(buf[1])<<8 != magic {
+		t.Fatalf("expected legacy magic in encoded output")
+	}
+
+	got, err := Decode(buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Nums[0] != 80 {
+		t.Errorf("Nums[0] = %d, want 80", got.Nums[0])
+	}
+}
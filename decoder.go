@@ -0,0 +1,239 @@
+package terminfo
+
+import "sync"
+
+// magic is the magic number for the legacy terminfo format, in which every
+// num field (in the header, the numbers section, and the extended numbers
+// section) is a 2-byte little-endian value. See magicWide for the ncurses
+// 6.1+ variant, which widens only the numbers sections.
+const magic = 0432
+
+// Header fields, in the order they appear in the 5 int16 values following
+// the magic number (in both the legacy and the wide format).
+const (
+	fieldNameSize = iota
+	fieldBoolCount
+	fieldNumCount
+	fieldStringCount
+	fieldTableSize
+)
+
+// Extended header fields, in the order they appear in the 5 values of the
+// extended header.
+const (
+	fieldExtBoolCount = iota
+	fieldExtNumCount
+	fieldExtStringCount
+	fieldExtOffsetCount
+	fieldExtTableSize
+)
+
+// capCountBool, capCountNum, and capCountString are the total number of
+// standard bool, num, and string capabilities this package knows about; a
+// file whose header declares fewer than this leaves the remaining slots
+// marked missing.
+var (
+	capCountBool   = len(BoolCapNames)
+	capCountNum    = len(NumCapNames)
+	capCountString = len(StringCapNames)
+)
+
+// termCache holds Terminfo entries loaded by Open and Load, keyed by every
+// name they were registered under.
+var termCache = struct {
+	sync.Mutex
+	db map[string]*Terminfo
+}{db: make(map[string]*Terminfo)}
+
+// decoder reads the sequence of fields making up a binary terminfo entry
+// out of buf, tracking the current read position in pos.
+type decoder struct {
+	buf []byte
+	len int
+	pos int
+}
+
+// readInt16 reads a single 2-byte little-endian value.
+func (d *decoder) readInt16() (int, error) {
+	if d.pos+2 > d.len {
+		return 0, ErrUnexpectedFileEnd
+	}
+	v := int(int16(uint16(d.buf[d.pos]) | uint16(d.buf[d.pos+1])<<8))
+	d.pos += 2
+	return v, nil
+}
+
+// readBytes reads n raw bytes.
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > d.len {
+		return nil, ErrUnexpectedFileEnd
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readBools reads n bool capabilities (out of cnt total slots), one byte
+// each: 0 is false, 1 is true, and -1 or -2 (as a signed byte) mark the
+// capability as absent or cancelled, both recorded in the returned missing
+// map. Any slot beyond n (the file predates that capability) is also
+// marked missing.
+func (d *decoder) readBools(n, cnt int) ([]bool, map[int]bool, error) {
+	bools := make([]bool, cnt)
+	m := make(map[int]bool)
+	for i := 0; i < cnt; i++ {
+		if i >= n {
+			m[i] = true
+			continue
+		}
+		if d.pos >= d.len {
+			return nil, nil, ErrUnexpectedFileEnd
+		}
+		v := int8(d.buf[d.pos])
+		d.pos++
+		switch v {
+		case -1, -2:
+			m[i] = true
+		default:
+			bools[i] = v != 0
+		}
+	}
+	return bools, m, nil
+}
+
+// readNums reads n legacy 2-byte little-endian num capabilities (out of cnt
+// total slots), consuming a single padding byte first if the current
+// position is odd. -1 marks an absent capability and -2 a cancelled one,
+// both recorded in the returned missing map; slots beyond n are likewise
+// marked missing.
+func (d *decoder) readNums(n, cnt int) ([]int, map[int]bool, error) {
+	if d.pos%2 != 0 {
+		d.pos++
+	}
+
+	nums := make([]int, cnt)
+	m := make(map[int]bool)
+	for i := 0; i < cnt; i++ {
+		if i >= n {
+			m[i] = true
+			continue
+		}
+		if d.pos+2 > d.len {
+			return nil, nil, ErrUnexpectedFileEnd
+		}
+		v := int(int16(uint16(d.buf[d.pos]) | uint16(d.buf[d.pos+1])<<8))
+		d.pos += 2
+		switch v {
+		case -1:
+			m[i] = true
+		case -2:
+			m[i] = true
+			v = 0
+		}
+		nums[i] = v
+	}
+	return nums, m, nil
+}
+
+// readStrings reads n string capability offsets (out of cnt total slots),
+// then the tableSize-byte string table those offsets index into, and
+// returns the decoded, NUL-terminated string for each. A negative offset
+// (-1 absent, -2 cancelled) or a slot beyond n marks that capability
+// missing.
+func (d *decoder) readStrings(n, tableSize, cnt int) ([]string, map[int]bool, error) {
+	offs := make([]int, cnt)
+	m := make(map[int]bool)
+	for i := 0; i < cnt; i++ {
+		if i >= n {
+			m[i] = true
+			continue
+		}
+		if d.pos+2 > d.len {
+			return nil, nil, ErrUnexpectedFileEnd
+		}
+		v := int(int16(uint16(d.buf[d.pos]) | uint16(d.buf[d.pos+1])<<8))
+		d.pos += 2
+		if v < 0 {
+			m[i] = true
+		}
+		offs[i] = v
+	}
+
+	if tableSize < 0 || d.pos+tableSize > d.len {
+		return nil, nil, ErrUnexpectedFileEnd
+	}
+	table := d.buf[d.pos : d.pos+tableSize]
+	d.pos += tableSize
+
+	strs := make([]string, cnt)
+	for i, off := range offs {
+		if m[i] || off < 0 || off >= len(table) {
+			continue
+		}
+		end := off
+		for end < len(table) && table[end] != 0 {
+			end++
+		}
+		strs[i] = string(table[off:end])
+	}
+
+	return strs, m, nil
+}
+
+// hasInvalidCaps reports whether the header array h contains a negative
+// field, which would make the rest of the entry unparseable.
+func hasInvalidCaps(h []int) bool {
+	for _, v := range h {
+		if v < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// capLength returns the number of bytes the names, bool, num, and string
+// sections described by header h must occupy, including the single
+// alignment padding byte before the num section when needed. numSize is 2
+// for the legacy format or 4 for the wide format, matching the width
+// readNumCaps will read the num section with.
+func capLength(h []int, numSize int) int {
+	n := h[fieldNameSize] + h[fieldBoolCount] + h[fieldNumCount]*numSize + h[fieldStringCount]*2 + h[fieldTableSize]
+	if (h[fieldNameSize]+h[fieldBoolCount])%2 != 0 {
+		n++
+	}
+	return n
+}
+
+// hasInvalidExtOffset reports whether the extended header array eh contains
+// a negative field.
+func hasInvalidExtOffset(eh []int) bool {
+	for _, v := range eh {
+		if v < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// extCapLength returns the number of bytes the extended bool, num, and
+// string sections described by extended header eh must occupy, including
+// the alignment padding byte before the extended num section when needed.
+// numSize is 2 for the legacy format or 4 for the wide format, matching the
+// width readNumCaps will read the extended num section with.
+func extCapLength(eh []int, numSize int) int {
+	n := eh[fieldExtBoolCount] + eh[fieldExtNumCount]*numSize + eh[fieldExtOffsetCount]*2 + eh[fieldExtTableSize]
+	if eh[fieldExtBoolCount]%2 != 0 {
+		n++
+	}
+	return n
+}
+
+// makemap builds a capability name to index map out of names, the order
+// they were decoded in.
+func makemap(names []string) map[string]int {
+	m := make(map[string]int, len(names))
+	for i, n := range names {
+		m[n] = i
+	}
+	return m
+}
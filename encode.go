@@ -0,0 +1,201 @@
+package terminfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// Encode serializes ti into the binary terminfo format that Decode reads:
+// the legacy format if ti has no extended capabilities, or the legacy
+// format followed by the extended header, bools, nums, and string table
+// otherwise. As with Decode, -1 marks an absent capability and -2 a
+// cancelled one; since Terminfo only tracks a single "missing" bit per
+// capability, both round-trip as -1.
+//
+// If any numeric capability (standard or extended) falls outside the
+// legacy 16-bit range, the ncurses 6.1+ wide format (magicWide, 4-byte
+// numbers) is emitted instead of silently truncating it; this mirrors the
+// wide-format support Decode has for reading such entries.
+func (ti *Terminfo) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+
+	wide := needsWide(ti.Nums, ti.NumsM) || needsWide(ti.ExtNums, nil)
+	m := magic
+	if wide {
+		m = magicWide
+	}
+	writeInt16(&buf, m)
+
+	names := strings.Join(ti.Names, "|") + "\x00"
+	numVals := buildNums(ti.Nums, ti.NumsM)
+	strOffsets, strTable := buildStringTable(ti.Strings, ti.StringsM)
+
+	writeInt16(&buf, len(names))
+	writeInt16(&buf, len(ti.Bools))
+	writeInt16(&buf, len(ti.Nums))
+	writeInt16(&buf, len(ti.Strings))
+	writeInt16(&buf, len(strTable))
+
+	buf.WriteString(names)
+	writeBools(&buf, ti.Bools, ti.BoolsM)
+
+	if buf.Len()%2 != 0 {
+		buf.WriteByte(0)
+	}
+
+	writeNums(&buf, numVals, wide)
+	for _, v := range strOffsets {
+		writeInt16(&buf, v)
+	}
+	buf.Write(strTable)
+
+	if len(ti.ExtBools) == 0 && len(ti.ExtNums) == 0 && len(ti.ExtStrings) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	if buf.Len()%2 != 0 {
+		buf.WriteByte(0)
+	}
+
+	extNumVals := buildNums(ti.ExtNums, nil)
+	extStrOffsets, extStrTable := buildStringTable(ti.ExtStrings, nil)
+
+	var nameTable []byte
+	var nameOffsets []int
+	for _, group := range [][]string{
+		sortedExtNames(ti.ExtBoolsNames),
+		sortedExtNames(ti.ExtNumsNames),
+		sortedExtNames(ti.ExtStringsNames),
+	} {
+		for _, n := range group {
+			nameOffsets = append(nameOffsets, len(nameTable))
+			nameTable = append(nameTable, n...)
+			nameTable = append(nameTable, 0)
+		}
+	}
+
+	count := len(ti.ExtBools) + len(ti.ExtNums) + 2*len(ti.ExtStrings)
+
+	writeInt16(&buf, len(ti.ExtBools))
+	writeInt16(&buf, len(ti.ExtNums))
+	writeInt16(&buf, len(ti.ExtStrings))
+	writeInt16(&buf, count)
+	writeInt16(&buf, len(extStrTable)+len(nameTable))
+
+	writeBools(&buf, ti.ExtBools, nil)
+	if buf.Len()%2 != 0 {
+		buf.WriteByte(0)
+	}
+
+	writeNums(&buf, extNumVals, wide)
+	for _, off := range extStrOffsets {
+		writeInt16(&buf, off)
+	}
+	for _, off := range nameOffsets {
+		writeInt16(&buf, len(extStrTable)+off)
+	}
+
+	buf.Write(extStrTable)
+	buf.Write(nameTable)
+
+	return buf.Bytes(), nil
+}
+
+// needsWide reports whether any non-missing entry of vals falls outside the
+// legacy 16-bit numeric capability range ([-32768, 32767]), requiring the
+// wide format to represent without truncation.
+func needsWide(vals []int, missing map[int]bool) bool {
+	for i, v := range vals {
+		if missing[i] {
+			continue
+		}
+		if v < -32768 || v > 32767 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNums appends vals to buf as 4-byte little-endian values if wide,
+// or 2-byte little-endian values otherwise.
+func writeNums(buf *bytes.Buffer, vals []int, wide bool) {
+	for _, v := range vals {
+		if wide {
+			writeInt32(buf, v)
+		} else {
+			writeInt16(buf, v)
+		}
+	}
+}
+
+// writeInt16 appends v to buf as a little-endian 2-byte value.
+func writeInt16(buf *bytes.Buffer, v int) {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], uint16(int16(v)))
+	buf.Write(b[:])
+}
+
+// writeInt32 appends v to buf as a little-endian 4-byte value.
+func writeInt32(buf *bytes.Buffer, v int) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(int32(v)))
+	buf.Write(b[:])
+}
+
+// writeBools appends one byte per entry of vals (1 or 0), treating any
+// index present in missing as absent (0) regardless of vals' value.
+func writeBools(buf *bytes.Buffer, vals []bool, missing map[int]bool) {
+	for i, b := range vals {
+		v := byte(0)
+		if b && !missing[i] {
+			v = 1
+		}
+		buf.WriteByte(v)
+	}
+}
+
+// buildNums encodes num capability values for the on-disk numbers section,
+// replacing any index present in missing with -1.
+func buildNums(vals []int, missing map[int]bool) []int {
+	out := make([]int, len(vals))
+	for i, v := range vals {
+		if missing[i] {
+			out[i] = -1
+			continue
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// buildStringTable encodes string capability values into the on-disk
+// representation: one offset per capability (-1 if index is present in
+// missing) and the concatenated, NUL-terminated string table those offsets
+// index into.
+func buildStringTable(vals []string, missing map[int]bool) (offsets []int, table []byte) {
+	offsets = make([]int, len(vals))
+	for i, v := range vals {
+		if missing[i] {
+			offsets[i] = -1
+			continue
+		}
+		offsets[i] = len(table)
+		table = append(table, v...)
+		table = append(table, 0)
+	}
+	return offsets, table
+}
+
+// sortedExtNames returns the keys of m ordered by their index value, so
+// that the resulting slice can be walked in the same order the indices
+// were originally assigned.
+func sortedExtNames(m map[string]int) []string {
+	out := make([]string, len(m))
+	for name, i := range m {
+		if i >= 0 && i < len(out) {
+			out[i] = name
+		}
+	}
+	return out
+}
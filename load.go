@@ -0,0 +1,222 @@
+package terminfo
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// defaultDirs are the built-in terminfo search directories tried when
+// $TERMINFO_DIRS contains an empty entry and as a last resort after all
+// environment-driven directories have been tried, mirroring ncurses'
+// compiled-in default search path.
+var defaultDirs = []string{
+	"/etc/terminfo",
+	"/lib/terminfo",
+	"/usr/share/terminfo",
+	"/usr/share/lib/terminfo",
+}
+
+// notFoundCache remembers names that were not found during a previous Load,
+// so that a missing terminal does not restat every search directory on
+// every call.
+var notFoundCache = struct {
+	sync.Mutex
+	m map[string]bool
+}{m: make(map[string]bool)}
+
+// Load loads the terminfo entry for name, searching the same locations and
+// in the same order as ncurses' setupterm: $TERMINFO (a single directory),
+// $HOME/.terminfo, each colon-separated entry of $TERMINFO_DIRS (an empty
+// entry meaning the compiled-in default directories), and finally the
+// built-in list of system terminfo directories. Each directory may either
+// be a standard terminfo tree (searched using both the single-letter and
+// hex-byte layouts, as Open does) or a cdb-format hashed terminfo database
+// (a terminfo.cdb inside it, or the entry itself a *.cdb file). Berkeley DB
+// terminfo.db files are not read: such a file is reported as not found.
+func Load(name string) (*Terminfo, error) {
+	if name == "" {
+		return nil, ErrEmptyTermName
+	}
+
+	notFoundCache.Lock()
+	missing := notFoundCache.m[name]
+	notFoundCache.Unlock()
+	if missing {
+		return nil, ErrFileNotFound
+	}
+
+	for _, dir := range searchDirs() {
+		ti, err := openDir(dir, name)
+		if err == nil {
+			return ti, nil
+		}
+		if err != ErrFileNotFound && err != ErrDatabaseDirectoryNotFound {
+			return nil, err
+		}
+	}
+
+	notFoundCache.Lock()
+	notFoundCache.m[name] = true
+	notFoundCache.Unlock()
+
+	return nil, ErrFileNotFound
+}
+
+// searchDirs builds the ordered list of directories (or database files) to
+// search, per the rules described on Load.
+func searchDirs() []string {
+	var dirs []string
+
+	if v := os.Getenv("TERMINFO"); v != "" {
+		dirs = append(dirs, v)
+	}
+
+	if h := os.Getenv("HOME"); h != "" {
+		dirs = append(dirs, path.Join(h, ".terminfo"))
+	}
+
+	if v, ok := os.LookupEnv("TERMINFO_DIRS"); ok {
+		for _, d := range strings.Split(v, ":") {
+			if d == "" {
+				dirs = append(dirs, defaultDirs...)
+				continue
+			}
+			dirs = append(dirs, d)
+		}
+	}
+
+	dirs = append(dirs, defaultDirs...)
+
+	return dirs
+}
+
+// openDir looks up name within dir, which may be a standard terminfo
+// directory tree or a cdb-format hashed terminfo database file (a
+// terminfo.cdb, or dir itself named *.cdb). ncurses' native terminfo.db,
+// a Berkeley DB hash file, is a different on-disk format that openDB does
+// not understand: a dir that is such a file reports the name not found, and
+// a terminfo.db inside a directory is ignored in favour of the tree lookup.
+func openDir(dir, name string) (*Terminfo, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, ErrDatabaseDirectoryNotFound
+	}
+	if !fi.IsDir() {
+		if strings.HasSuffix(dir, ".cdb") {
+			return openDB(dir, name)
+		}
+		return nil, ErrFileNotFound
+	}
+
+	if db := path.Join(dir, "terminfo.cdb"); isFile(db) {
+		return openDB(db, name)
+	}
+
+	return Open(dir, name)
+}
+
+// openDB looks up name's compiled entry within the hashed terminfo database
+// file at file, currently supporting the cdb (constant database) format.
+func openDB(file, name string) (*Terminfo, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, ErrFileNotFound
+	}
+	defer f.Close()
+
+	buf, err := cdbLookup(f, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ti, err := Decode(buf)
+	if err != nil {
+		return nil, err
+	}
+	ti.File = file
+
+	termCache.Lock()
+	for _, n := range ti.Names {
+		termCache.db[n] = ti
+	}
+	termCache.Unlock()
+
+	return ti, nil
+}
+
+// cdbLookup reads the djb cdb (constant database) record for key out of f,
+// returning ErrFileNotFound if no such record exists.
+func cdbLookup(f *os.File, key string) ([]byte, error) {
+	buf, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) < 2048 {
+		return nil, ErrFileNotFound
+	}
+
+	h := cdbHash(key)
+	tableNum := h % 256
+
+	tablePos := le32(buf, tableNum*8)
+	tableLen := le32(buf, tableNum*8+4)
+	if tableLen == 0 {
+		return nil, ErrFileNotFound
+	}
+
+	start := (h >> 8) % uint32(tableLen)
+	for i := uint32(0); i < tableLen; i++ {
+		slot := tablePos + ((start + i) % uint32(tableLen) * 8)
+		if slot+8 > uint32(len(buf)) {
+			return nil, ErrFileNotFound
+		}
+
+		slotHash := le32(buf, slot)
+		recPos := le32(buf, slot+4)
+		if recPos == 0 {
+			// empty slot: key is not present
+			return nil, ErrFileNotFound
+		}
+		if slotHash != h {
+			continue
+		}
+
+		if recPos+8 > uint32(len(buf)) {
+			return nil, ErrFileNotFound
+		}
+		klen := le32(buf, recPos)
+		vlen := le32(buf, recPos+4)
+		if klen > uint32(len(buf))-recPos-8 || vlen > uint32(len(buf))-recPos-8-klen {
+			return nil, ErrFileNotFound
+		}
+		recKey := buf[recPos+8 : recPos+8+klen]
+		if string(recKey) == key {
+			return buf[recPos+8+klen : recPos+8+klen+vlen], nil
+		}
+	}
+
+	return nil, ErrFileNotFound
+}
+
+// cdbHash computes djb's cdb hash of key.
+func cdbHash(key string) uint32 {
+	h := uint32(5381)
+	for i := 0; i < len(key); i++ {
+		h = ((h << 5) + h) ^ uint32(key[i])
+	}
+	return h
+}
+
+// le32 reads a 4-byte little-endian value out of buf at pos.
+func le32(buf []byte, pos uint32) uint32 {
+	return uint32(buf[pos]) | uint32(buf[pos+1])<<8 | uint32(buf[pos+2])<<16 | uint32(buf[pos+3])<<24
+}
+
+// isFile reports whether name exists and is a regular file.
+func isFile(name string) bool {
+	fi, err := os.Stat(name)
+	return err == nil && !fi.IsDir()
+}
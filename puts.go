@@ -0,0 +1,117 @@
+package terminfo
+
+import (
+	"io"
+	"strings"
+)
+
+// Puts emits s to w, expanding inline padding indications of the form
+// $<delay[*][/]> (delay in milliseconds, optionally fractional) into a
+// suitable number of padding characters based on baud. '*' scales the
+// delay proportionally by lines, for capabilities (such as scrolling) whose
+// cost is per-line. '/' marks the padding as mandatory even when the
+// terminal does its own flow control. Padding is otherwise only emitted
+// when the terminal does not advertise xon/xoff flow control and baud
+// exceeds the terminal's PaddingBaudRate, matching terminfo(5).
+func (ti *Terminfo) Puts(w io.Writer, s string, lines, baud int) (int, error) {
+	var n int
+	for {
+		start := strings.Index(s, "$<")
+		if start == -1 {
+			c, err := io.WriteString(w, s)
+			return n + c, err
+		}
+
+		c, err := io.WriteString(w, s[:start])
+		n += c
+		if err != nil {
+			return n, err
+		}
+		s = s[start+2:]
+
+		end := strings.IndexByte(s, '>')
+		if end == -1 {
+			// unterminated: emit the rest unadulterated
+			c, err = io.WriteString(w, "$<"+s)
+			return n + c, err
+		}
+		val := s[:end]
+		s = s[end+1:]
+
+		ms, unit := 0, 1000
+		var dot, mandatory, asterisk bool
+		for _, ch := range val {
+			switch {
+			case ch >= '0' && ch <= '9':
+				ms = ms*10 + int(ch-'0')
+				if dot {
+					unit *= 10
+				}
+			case ch == '.' && !dot:
+				dot = true
+			case ch == '*' && !asterisk:
+				ms *= lines
+				asterisk = true
+			case ch == '/':
+				mandatory = true
+			}
+		}
+
+		if !mandatory && (ti.Bools[XonXoff] || baud <= int(ti.Nums[PaddingBaudRate])) {
+			continue
+		}
+
+		pad := ti.Strings[PadChar]
+		if pad == "" {
+			pad = "\x00"
+		}
+		if count := (baud / 8 / unit) * ms; count > 0 {
+			c, err = io.WriteString(w, strings.Repeat(pad, count))
+			n += c
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+}
+
+// Writer wraps an io.Writer together with the Terminfo, baud rate, and line
+// count needed to pad capability strings correctly, so that callers don't
+// have to thread those three values through every write.
+type Writer struct {
+	W     io.Writer
+	TI    *Terminfo
+	Baud  int
+	Lines int
+}
+
+// Write implements io.Writer, expanding any padding indications s contains.
+// Per the io.Writer contract, the returned count is always 0 <= n <= len(p);
+// since Puts may write more bytes than len(p) to w (the injected padding),
+// it is not itself that count.
+func (w *Writer) Write(p []byte) (int, error) {
+	_, err := w.TI.Puts(w.W, string(p), w.Lines, w.Baud)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteString is like Write, but takes a string directly and, not being
+// constrained by the io.Writer contract, returns the actual number of bytes
+// Puts wrote to w (which may exceed len(s) due to padding).
+func (w *Writer) WriteString(s string) (int, error) {
+	return w.TI.Puts(w.W, s, w.Lines, w.Baud)
+}
+
+// Goto moves the cursor to row, col.
+func (w *Writer) Goto(row, col int) error {
+	_, err := w.WriteString(w.TI.Goto(row, col))
+	return err
+}
+
+// SetFg sets the foreground color to fg.
+func (w *Writer) SetFg(fg int) error {
+	_, err := w.WriteString(w.TI.Parm(SetAForeground, fg))
+	return err
+}
@@ -0,0 +1,98 @@
+package terminfo
+
+import (
+	"strings"
+	"testing"
+)
+
+// newPutsTerminfo returns a minimal Terminfo sized to index the bool/num/
+// string capabilities Puts relies on (XonXoff, PaddingBaudRate, PadChar).
+func newPutsTerminfo(xonXoff bool, paddingBaudRate int, padChar string) *Terminfo {
+	n := int(XonXoff) + 1
+	bools := make([]bool, n)
+	bools[XonXoff] = xonXoff
+
+	m := int(PaddingBaudRate) + 1
+	nums := make([]int, m)
+	nums[PaddingBaudRate] = paddingBaudRate
+
+	s := int(PadChar) + 1
+	strs := make([]string, s)
+	strs[PadChar] = padChar
+
+	return &Terminfo{Bools: bools, Nums: nums, Strings: strs}
+}
+
+func TestPutsBasicPadding(t *testing.T) {
+	ti := newPutsTerminfo(false, 0, "\x00")
+
+	var sb strings.Builder
+	n, err := ti.Puts(&sb, "a$<10>b", 1, 9600)
+	if err != nil {
+		t.Fatalf("Puts: %v", err)
+	}
+
+	want := "a" + strings.Repeat("\x00", (9600/8/1000)*10) + "b"
+	if sb.String() != want || n != len(want) {
+		t.Errorf("Puts = %q (n=%d), want %q (n=%d)", sb.String(), n, want, len(want))
+	}
+}
+
+func TestPutsAsteriskScalesByLines(t *testing.T) {
+	ti := newPutsTerminfo(false, 0, "\x00")
+
+	var sb strings.Builder
+	if _, err := ti.Puts(&sb, "x$<2*>y", 5, 9600); err != nil {
+		t.Fatalf("Puts: %v", err)
+	}
+
+	want := "x" + strings.Repeat("\x00", (9600/8/1000)*2*5) + "y"
+	if sb.String() != want {
+		t.Errorf("Puts = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestPutsMandatorySlashOverridesXonXoff(t *testing.T) {
+	// xon/xoff flow control is on, which would normally suppress padding,
+	// but the '/' modifier forces it anyway.
+	ti := newPutsTerminfo(true, 0, "\x00")
+
+	var sb strings.Builder
+	if _, err := ti.Puts(&sb, "$<5/>", 1, 9600); err != nil {
+		t.Fatalf("Puts: %v", err)
+	}
+
+	want := strings.Repeat("\x00", (9600/8/1000)*5)
+	if sb.String() != want {
+		t.Errorf("Puts = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestPutsNoPaddingBelowBaudRate(t *testing.T) {
+	ti := newPutsTerminfo(false, 19200, "\x00")
+
+	var sb strings.Builder
+	if _, err := ti.Puts(&sb, "a$<50>b", 1, 9600); err != nil {
+		t.Fatalf("Puts: %v", err)
+	}
+
+	if sb.String() != "ab" {
+		t.Errorf("Puts = %q, want %q (no padding below PaddingBaudRate)", sb.String(), "ab")
+	}
+}
+
+func TestPutsDefaultsPadCharToNUL(t *testing.T) {
+	// PadChar left unset (empty string): Puts must default to NUL rather
+	// than padding with nothing.
+	ti := newPutsTerminfo(false, 0, "")
+
+	var sb strings.Builder
+	if _, err := ti.Puts(&sb, "$<10>", 1, 9600); err != nil {
+		t.Fatalf("Puts: %v", err)
+	}
+
+	want := strings.Repeat("\x00", (9600/8/1000)*10)
+	if sb.String() != want {
+		t.Errorf("Puts = %q, want %q", sb.String(), want)
+	}
+}
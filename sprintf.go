@@ -0,0 +1,465 @@
+package terminfo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnterminatedSequence is the error returned by Parm/CapSprintf when a
+// parameterized string capability contains a malformed or unterminated "%"
+// directive.
+var ErrUnterminatedSequence = errors.New("terminfo: unterminated % sequence")
+
+// Sprintf evaluates the parameterized string capability s against p. It is
+// equivalent to Parm, and is kept so existing callers that built a
+// StringCapType directly continue to work.
+func (ti *Terminfo) Sprintf(s StringCapType, p ...interface{}) string {
+	return ti.Parm(s, p...)
+}
+
+// Parm evaluates the parameterized string capability cap against args and
+// returns the resulting control sequence. A malformed parameter string is
+// silently turned into "" here; use ParmErr to see the error. An invalid
+// cap index also yields "", with no error from either method.
+func (ti *Terminfo) Parm(cap StringCapType, args ...interface{}) string {
+	s, _ := ti.ParmErr(cap, args...)
+	return s
+}
+
+// ParmErr is like Parm, but additionally reports an error when the
+// capability string is malformed.
+func (ti *Terminfo) ParmErr(cap StringCapType, args ...interface{}) (string, error) {
+	i := int(cap)
+	if i < 0 || i >= len(ti.Strings) {
+		return "", nil
+	}
+	return ti.tparm(ti.Strings[i], args...)
+}
+
+// CapSprintf formats the standard or extended string capability named name
+// against args, searching standard capability names first and then the
+// extended string capabilities. It returns "" if no such capability exists.
+func (ti *Terminfo) CapSprintf(name string, args ...interface{}) string {
+	for i, n := range StringCapNames {
+		if n == name {
+			return ti.Parm(StringCapType(i), args...)
+		}
+	}
+
+	if i, ok := ti.ExtStringsNames[name]; ok {
+		s, _ := ti.tparm(ti.ExtStrings[i], args...)
+		return s
+	}
+
+	return ""
+}
+
+// tparm is the parameterized-string stack machine described in
+// terminfo(5). It supports:
+//
+//	%%            literal '%'
+//	%c %s         pop and emit as a byte / string
+//	%d %o %x %X   pop and emit an integer, with optional printf-style
+//	              [:]flags[width[.precision]] (e.g. %:-16.16s, %02x)
+//	%p[1-9]       push the i'th argument (1-indexed)
+//	%P[a-z]       pop into dynamic variable a-z (reset on every call)
+//	%g[a-z]       push dynamic variable a-z
+//	%P[A-Z]       pop into static variable A-Z (persists on ti.StaticVars)
+//	%g[A-Z]       push static variable A-Z
+//	%'c'          push a character literal
+//	%{nn}         push an integer literal
+//	%l            pop a string, push its length
+//	%+ %- %* %/ %m
+//	%& %| %^
+//	%= %< %>
+//	%A %O
+//	%! %~         unary not / bitwise complement
+//	%i            increment the first two parameters (cup is 0-origin,
+//	              most terminals are 1-origin)
+//	%? expr %t then [%e else] %;   (possibly nested) conditional
+//
+// bool/int/string Go arguments are coerced to int/int/[]byte respectively.
+func (ti *Terminfo) tparm(s string, args ...interface{}) (string, error) {
+	a := make([]interface{}, len(args))
+	for i, v := range args {
+		switch x := v.(type) {
+		case bool:
+			a[i] = boolInt(x)
+		case string:
+			a[i] = []byte(x)
+		default:
+			a[i] = v
+		}
+	}
+
+	var stack parmStack
+	var dynamic [26]interface{}
+	var buf bytes.Buffer
+
+	n := len(s)
+	for i := 0; i < n; {
+		c := s[i]
+		if c != '%' {
+			buf.WriteByte(c)
+			i++
+			continue
+		}
+
+		i++
+		if i >= n {
+			return "", ErrUnterminatedSequence
+		}
+
+		// printf-style formatted output, e.g. %d, %02x, %:-16.16s
+		if strings.IndexByte(":.# ", s[i]) >= 0 || (s[i] >= '0' && s[i] <= '9') {
+			spec, verb, next, ok := parseFormatSpec(s, i)
+			if ok {
+				switch verb {
+				case 'c':
+					fmt.Fprintf(&buf, "%"+spec+"c", stack.popInt())
+				case 's':
+					fmt.Fprintf(&buf, "%"+spec+"s", stack.popBytes())
+				default:
+					fmt.Fprintf(&buf, "%"+spec+string(verb), stack.popInt())
+				}
+				i = next
+				continue
+			}
+		}
+
+		switch s[i] {
+		case '%':
+			buf.WriteByte('%')
+			i++
+
+		case 'c':
+			buf.WriteByte(byte(stack.popInt()))
+			i++
+		case 's':
+			buf.Write(stack.popBytes())
+			i++
+		case 'd':
+			fmt.Fprintf(&buf, "%d", stack.popInt())
+			i++
+		case 'o':
+			fmt.Fprintf(&buf, "%o", stack.popInt())
+			i++
+		case 'x':
+			fmt.Fprintf(&buf, "%x", stack.popInt())
+			i++
+		case 'X':
+			fmt.Fprintf(&buf, "%X", stack.popInt())
+			i++
+
+		case 'i':
+			if len(a) > 0 {
+				if v, ok := a[0].(int); ok {
+					a[0] = v + 1
+				}
+			}
+			if len(a) > 1 {
+				if v, ok := a[1].(int); ok {
+					a[1] = v + 1
+				}
+			}
+			i++
+
+		case 'p':
+			i++
+			if i >= n || s[i] < '1' || s[i] > '9' {
+				return "", ErrUnterminatedSequence
+			}
+			idx := int(s[i] - '1')
+			i++
+			if idx < len(a) {
+				stack.push(a[idx])
+			} else {
+				stack.pushInt(0)
+			}
+
+		case 'P':
+			i++
+			if i >= n {
+				return "", ErrUnterminatedSequence
+			}
+			v := stack.pop()
+			switch {
+			case s[i] >= 'a' && s[i] <= 'z':
+				dynamic[s[i]-'a'] = v
+			case s[i] >= 'A' && s[i] <= 'Z':
+				ti.StaticVars[s[i]-'A'] = v
+			}
+			i++
+
+		case 'g':
+			i++
+			if i >= n {
+				return "", ErrUnterminatedSequence
+			}
+			switch {
+			case s[i] >= 'a' && s[i] <= 'z':
+				stack.push(dynamic[s[i]-'a'])
+			case s[i] >= 'A' && s[i] <= 'Z':
+				stack.push(ti.StaticVars[s[i]-'A'])
+			}
+			i++
+
+		case '\'':
+			i++
+			if i+1 >= n || s[i+1] != '\'' {
+				return "", ErrUnterminatedSequence
+			}
+			stack.pushInt(int(s[i]))
+			i += 2
+
+		case '{':
+			i++
+			start := i
+			for i < n && s[i] != '}' {
+				i++
+			}
+			if i >= n {
+				return "", ErrUnterminatedSequence
+			}
+			v, err := strconv.Atoi(s[start:i])
+			if err != nil {
+				return "", fmt.Errorf("terminfo: invalid %%{} literal: %w", err)
+			}
+			i++
+			stack.pushInt(v)
+
+		case 'l':
+			stack.pushInt(len(stack.popBytes()))
+			i++
+
+		case '+', '-', '*', '/', 'm', '&', '|', '^', '=', '<', '>', 'A', 'O':
+			op := s[i]
+			i++
+			y, x := stack.popInt(), stack.popInt()
+			var v int
+			switch op {
+			case '+':
+				v = x + y
+			case '-':
+				v = x - y
+			case '*':
+				v = x * y
+			case '/':
+				if y == 0 {
+					return "", errors.New("terminfo: division by zero")
+				}
+				v = x / y
+			case 'm':
+				if y == 0 {
+					return "", errors.New("terminfo: division by zero")
+				}
+				v = x % y
+			case '&':
+				v = x & y
+			case '|':
+				v = x | y
+			case '^':
+				v = x ^ y
+			case '=':
+				v = boolInt(x == y)
+			case '<':
+				v = boolInt(x < y)
+			case '>':
+				v = boolInt(x > y)
+			case 'A':
+				v = boolInt(x != 0 && y != 0)
+			case 'O':
+				v = boolInt(x != 0 || y != 0)
+			}
+			stack.pushInt(v)
+
+		case '!':
+			stack.pushInt(boolInt(stack.popInt() == 0))
+			i++
+		case '~':
+			stack.pushInt(^stack.popInt())
+			i++
+
+		case '?':
+			i++
+		case 't':
+			i++
+			if stack.popInt() == 0 {
+				next, which, err := skipCond(s, i)
+				if err != nil {
+					return "", err
+				}
+				i = next
+				if which != 'e' {
+					// no else branch: conditional already closed
+					continue
+				}
+			}
+		case 'e':
+			i++
+			next, err := skipToSemi(s, i)
+			if err != nil {
+				return "", err
+			}
+			i = next
+		case ';':
+			i++
+
+		default:
+			return "", fmt.Errorf("terminfo: unsupported %% sequence %%%c", s[i])
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// parseFormatSpec parses a printf-style format spec starting at i (which
+// must point at ':', '.', '#', ' ' or a digit) of the form [:]flags[width[.precision]]
+// followed by one of 'c', 'd', 'o', 's', 'x', 'X'. It returns the go-fmt
+// compatible spec (without the verb), the verb, the index just past the
+// verb, and whether a valid spec was found.
+func parseFormatSpec(s string, i int) (spec string, verb byte, next int, ok bool) {
+	n := len(s)
+	start := i
+	if s[i] == ':' {
+		i++
+	}
+	for i < n && strings.IndexByte("-+ #0", s[i]) >= 0 {
+		i++
+	}
+	for i < n && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i < n && s[i] == '.' {
+		i++
+		for i < n && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	}
+	if i >= n || strings.IndexByte("cdoxXs", s[i]) < 0 {
+		return "", 0, 0, false
+	}
+
+	specStart := start
+	if s[specStart] == ':' {
+		specStart++
+	}
+	return s[specStart:i], s[i], i + 1, true
+}
+
+// skipCond scans s starting at i, which must be just past a "%t"
+// directive, for the next %e or %; belonging to the same %? group, skipping
+// over any nested %? ... %; groups. It returns the index just past the
+// matched directive and which one ('e' or ';') was found.
+func skipCond(s string, i int) (int, byte, error) {
+	depth := 0
+	for i < len(s) {
+		if s[i] != '%' {
+			i++
+			continue
+		}
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '?':
+			depth++
+			i++
+		case ';':
+			if depth == 0 {
+				return i + 1, ';', nil
+			}
+			depth--
+			i++
+		case 'e':
+			if depth == 0 {
+				return i + 1, 'e', nil
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return 0, 0, errors.New("terminfo: unterminated %? conditional")
+}
+
+// skipToSemi scans s starting at i, which must be just past an "%e"
+// directive, for the matching %; , skipping over any nested
+// %? ... %; groups. It returns the index just past the matched %;.
+func skipToSemi(s string, i int) (int, error) {
+	depth := 0
+	for i < len(s) {
+		if s[i] != '%' {
+			i++
+			continue
+		}
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '?':
+			depth++
+			i++
+		case ';':
+			if depth == 0 {
+				return i + 1, nil
+			}
+			depth--
+			i++
+		default:
+			i++
+		}
+	}
+	return 0, errors.New("terminfo: unterminated %e without %;")
+}
+
+// boolInt converts a bool to 0 or 1, as terminfo parameter strings do.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parmStack is the value stack used while evaluating a parameterized
+// string. Each entry is either an int or a []byte.
+type parmStack []interface{}
+
+func (st *parmStack) push(v interface{}) { *st = append(*st, v) }
+func (st *parmStack) pushInt(v int)      { st.push(v) }
+
+func (st *parmStack) pop() interface{} {
+	n := len(*st)
+	if n == 0 {
+		return 0
+	}
+	v := (*st)[n-1]
+	*st = (*st)[:n-1]
+	return v
+}
+
+func (st *parmStack) popInt() int {
+	switch v := st.pop().(type) {
+	case int:
+		return v
+	case []byte:
+		n, _ := strconv.Atoi(string(v))
+		return n
+	}
+	return 0
+}
+
+func (st *parmStack) popBytes() []byte {
+	switch v := st.pop().(type) {
+	case []byte:
+		return v
+	case int:
+		return []byte(strconv.Itoa(v))
+	}
+	return nil
+}
@@ -81,13 +81,31 @@ type Terminfo struct {
 
 	// ExtStringsNames is the map of extended string capabilities to their index.
 	ExtStringsNames map[string]int
+
+	// StaticVars holds the terminfo parameter-string static variables
+	// (%PA-%PZ / %gA-%gZ), which persist across calls to Parm on this
+	// Terminfo.
+	StaticVars [26]interface{}
 }
 
+// magicWide is the magic number used by the ncurses 6.1+ "wide" terminfo
+// format, in which every value of the numbers section and the extended
+// numbers section is stored as a 4-byte little-endian value instead of the
+// legacy 2-byte value. The header and extended header fields and the string
+// offsets stay 2-byte values in both formats.
+const magicWide = 01036
+
+// maxFileSize is a sanity limit on the size of a terminfo file. Wide-format
+// entries with many 32-bit and extended capabilities routinely exceed the
+// 4096 byte limit the legacy format was bound by, so this is set generously
+// higher.
+const maxFileSize = 32768
+
 // Decode decodes the terminfo data contained in buf.
 func Decode(buf []byte) (*Terminfo, error) {
 	var err error
 
-	if len(buf) >= 4096 {
+	if len(buf) >= maxFileSize {
 		return nil, ErrInvalidFileSize
 	}
 
@@ -101,7 +119,12 @@ func Decode(buf []byte) (*Terminfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	if m != magic {
+	var wide bool
+	switch m {
+	case magic:
+	case magicWide:
+		wide = true
+	default:
 		return nil, ErrInvalidMagic
 	}
 
@@ -117,7 +140,11 @@ func Decode(buf []byte) (*Terminfo, error) {
 	}
 
 	// check remaining length
-	if d.len-d.pos < capLength(h) {
+	numSize := 2
+	if wide {
+		numSize = 4
+	}
+	if d.len-d.pos < capLength(h, numSize) {
 		return nil, ErrUnexpectedFileEnd
 	}
 
@@ -134,7 +161,7 @@ func Decode(buf []byte) (*Terminfo, error) {
 	}
 
 	// read num capabilities
-	nums, numsM, err := d.readNums(h[fieldNumCount], capCountNum)
+	nums, numsM, err := d.readNumCaps(wide, h[fieldNumCount], capCountNum)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +199,7 @@ func Decode(buf []byte) (*Terminfo, error) {
 	}
 
 	// check extended lengths in extended header
-	if d.len-d.pos != extCapLength(eh) {
+	if d.len-d.pos != extCapLength(eh, numSize) {
 		return nil, ErrInvalidExtendedHeader
 	}
 
@@ -183,7 +210,7 @@ func Decode(buf []byte) (*Terminfo, error) {
 	}
 
 	// read extended nums
-	ti.ExtNums, _, err = d.readNums(eh[fieldExtNumCount], eh[fieldExtNumCount])
+	ti.ExtNums, _, err = d.readNumCaps(wide, eh[fieldExtNumCount], eh[fieldExtNumCount])
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +233,53 @@ func Decode(buf []byte) (*Terminfo, error) {
 	return ti, nil
 }
 
+// readNumCaps reads n num capabilities (out of cnt total slots), using
+// 4-byte little-endian values when wide is true (the ncurses 6.1+ format)
+// and the legacy 2-byte values otherwise.
+func (d *decoder) readNumCaps(wide bool, n, cnt int) ([]int, map[int]bool, error) {
+	if !wide {
+		return d.readNums(n, cnt)
+	}
+	return d.readNums32(n, cnt)
+}
+
+// readNums32 reads n 4-byte little-endian num capabilities (out of cnt
+// total slots). As with the legacy 2-byte reader, -1 marks an absent
+// capability and -2 a cancelled one; both are recorded in the returned
+// missing map, as are slots beyond n. The alignment rule is unchanged: a single padding byte is
+// consumed first if the current position is odd.
+func (d *decoder) readNums32(n, cnt int) ([]int, map[int]bool, error) {
+	if d.pos%2 != 0 {
+		d.pos++
+	}
+
+	nums := make([]int, cnt)
+	m := make(map[int]bool)
+	for i := 0; i < cnt; i++ {
+		if i >= n {
+			m[i] = true
+			continue
+		}
+		if d.pos+4 > d.len {
+			return nil, nil, ErrUnexpectedFileEnd
+		}
+
+		v := int(int32(uint32(d.buf[d.pos]) | uint32(d.buf[d.pos+1])<<8 | uint32(d.buf[d.pos+2])<<16 | uint32(d.buf[d.pos+3])<<24))
+		d.pos += 4
+
+		switch v {
+		case -1:
+			m[i] = true
+		case -2:
+			m[i] = true
+			v = 0
+		}
+		nums[i] = v
+	}
+
+	return nums, m, nil
+}
+
 // Open reads the terminfo file name from the specified directory dir.
 func Open(dir, name string) (*Terminfo, error) {
 	var err error
@@ -244,94 +318,15 @@ func Open(dir, name string) (*Terminfo, error) {
 	return ti, nil
 }
 
-func (ti *Terminfo) Sprintf(s StringCapType, p ...interface{}) string {
-	return Sprintf(ti.Strings[int(s)], p...)
-}
-
-func (ti *Terminfo) CapSprintf(name string, p ...interface{}) string {
-	return ""
-}
-
 // Goto returns a string suitable for addressing the cursor at the given
 // row and column. The origin 0, 0 is in the upper left corner of the screen.
 func (ti *Terminfo) Goto(row, col int) string {
-	return ti.Sprintf(CursorAddress, row, col)
+	return ti.Parm(CursorAddress, row, col)
 }
 
-// Puts emits the string to the writer, but expands inline padding indications
-// (of the form $<[delay]> where [delay] is msec) to a suitable number of
-// padding characters (usually null bytes) based upon the supplied baud. At
-// high baud rates, more padding characters will be inserted.
-/*func (ti *Terminfo) Puts(w io.Writer, s string, lines, baud int) (int, error) {
-	var err error
-	for {
-		start := strings.Index(s, "$<")
-		if start == -1 {
-			// most strings don't need padding, which is good news!
-			return io.WriteString(w, s)
-		}
-
-		end := strings.Index(s, ">")
-		if end == -1 {
-			// unterminated... just emit bytes unadulterated.
-			return io.WriteString(w, "$<"+s)
-		}
-
-		var c int
-		c, err = io.WriteString(w, s[:start])
-		if err != nil {
-			return n + c, err
-		}
-		n += c
-
-		s = s[start+2:]
-		val := s[:end]
-		s = s[end+1:]
-		var ms int
-		var dot, mandatory, asterisk bool
-		unit := 1000
-		for _, ch := range val {
-			switch {
-			case ch >= '0' && ch <= '9':
-				ms = (ms * 10) + int(ch-'0')
-				if dot {
-					unit *= 10
-				}
-			case ch == '.' && !dot:
-				dot = true
-			case ch == '*' && !asterisk:
-				ms *= lines
-				asterisk = true
-			case ch == '/':
-				mandatory = true
-			default:
-				break
-			}
-		}
-
-		z, pad := ((baud/8)/unit)*ms, ti.Strings[PadChar]
-		b := make([]byte, len(pad)*z)
-		for bp := copy(b, pad); bp < len(b); bp *= 2 {
-			copy(b[bp:], b[:bp])
-		}
-
-		if (!ti.Bools[XonXoff] && baud > int(ti.Nums[PaddingBaudRate])) || mandatory {
-			c, err = w.Write(b)
-			if err != nil {
-				return n + c, err
-			}
-			n += c
-		}
-	}
-
-	return n, nil
-}*/
-
 // Color takes a foreground and background color and returns string that sets
 // them for this terminal.
-//
-// TODO redo with styles integer
-/*func (ti *Terminfo) Color(fg, bg int) (rv string) {
+func (ti *Terminfo) Color(fg, bg int) (rv string) {
 	maxColors := int(ti.Nums[MaxColors])
 
 	// map bright colors to lower versions if the color table only holds 8.
@@ -353,4 +348,4 @@ func (ti *Terminfo) Goto(row, col int) string {
 	}
 
 	return
-}*/
+}